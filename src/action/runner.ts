@@ -1,12 +1,19 @@
-import {getInput, setFailed, warning} from '@actions/core'
+import {getInput, getMultilineInput, setFailed, warning} from '@actions/core'
+// eslint-disable-next-line camelcase
+import {context as github_context} from '@actions/github'
 
 import {ChatBot} from '../bot/chat-bot'
+import {ResponseCache} from '../bot/response-cache'
+import {UsageTracker} from '../bot/usage-tracker'
 import {Options} from '../config/options'
 import {PromptLibrary} from '../prompts/templates'
 import {handleReviewComment} from '../review/comment-responder'
 import {ReviewOrchestrator} from '../review/review-orchestrator'
 import {ProviderType} from '../bot/providers/provider-factory'
 
+// eslint-disable-next-line camelcase
+const context = github_context
+
 const attachProcessGuards = (): void => {
   process
     .on('unhandledRejection', (reason, promise) => {
@@ -41,9 +48,28 @@ const buildOptions = (): Options =>
       getInput('gemini_light_model') ||
       getInput('gemini_heavy_model') ||
       '',
+    anthropicModel: getInput('anthropic_model'),
+    ollamaModel: getInput('ollama_model'),
+    ollamaBaseUrl: getInput('ollama_base_url'),
     enableApplySuggestions: getInput('enable_apply_suggestions'),
     maxSuggestionLines: getInput('max_suggestion_lines'),
-    maxSuggestionsPerFile: getInput('max_suggestions_per_file')
+    maxSuggestionsPerFile: getInput('max_suggestions_per_file'),
+    notifyWebhookUrl: getInput('notify_webhook_url'),
+    notifySlackWebhookUrl: getInput('notify_slack_webhook_url'),
+    notifyMinReviewComments: getInput('notify_min_review_comments'),
+    failOnReviewComments: getInput('fail_on_review_comments'),
+    promptCostPer1K: getInput('prompt_cost_per_1k_tokens'),
+    completionCostPer1K: getInput('completion_cost_per_1k_tokens'),
+    maxCost: getInput('max_cost'),
+    promptTemplatesDir: getInput('prompt_templates_dir'),
+    pathFilters: getMultilineInput('path_filters'),
+    reviewGeneratedFiles: getInput('review_generated_files'),
+    responseCacheDir: getInput('response_cache_dir'),
+    modelRetries: getInput('model_retries'),
+    modelTimeoutMS: getInput('model_timeout_ms'),
+    modelConcurrencyLimit: getInput('model_concurrency_limit'),
+    modelRequestsPerMinute: getInput('model_requests_per_minute'),
+    githubConcurrencyLimit: getInput('github_concurrency_limit')
   })
 
 const resolveProviderType = (requested: ProviderType): ProviderType => {
@@ -63,6 +89,13 @@ const resolveProviderType = (requested: ProviderType): ProviderType => {
     return 'openai'
   }
 
+  const hasAnthropic =
+    Boolean(process.env.ANTHROPIC_API_KEY) ||
+    Boolean(getInput('anthropic_api_key'))
+  if (hasAnthropic) {
+    return 'anthropic'
+  }
+
   return 'auto'
 }
 
@@ -79,8 +112,17 @@ const createBots = (
   options.aiProvider = providerType
   options.updateSelectedModel(model)
 
+  const usage = new UsageTracker({
+    promptCostPer1K: options.promptCostPer1K,
+    completionCostPer1K: options.completionCostPer1K,
+    maxCost: options.maxCost
+  })
+  const cache = options.responseCacheDir
+    ? new ResponseCache(options.responseCacheDir)
+    : null
+
   try {
-    lightBot = new ChatBot(options, providerType, model)
+    lightBot = new ChatBot(options, providerType, model, usage, cache)
   } catch (error: any) {
     warning(
       `Skipped: failed to create summary bot, please check your API keys: ${error}, backtrace: ${error.stack}`
@@ -88,20 +130,34 @@ const createBots = (
   }
 
   try {
-    heavyBot = new ChatBot(options, providerType, model)
+    heavyBot = new ChatBot(options, providerType, model, usage, cache)
   } catch (error: any) {
     warning(
       `Skipped: failed to create review bot, please check your API keys: ${error}, backtrace: ${error.stack}`
     )
   }
 
+  if (heavyBot) {
+    options.updateTokenLimits(heavyBot.getModelInfo())
+  }
+
   return {lightBot, heavyBot}
 }
 
-const buildPrompts = (options: Options): PromptLibrary => {
+const buildPrompts = async (options: Options): Promise<PromptLibrary> => {
   const prompts = new PromptLibrary()
   prompts.summarize = options.summarizePrompt
   prompts.summarizeReleaseNotes = options.summarizeReleaseNotesPrompt
+  if (options.promptTemplatesDir) {
+    const baseSha = context.payload.pull_request?.base?.sha
+    if (baseSha) {
+      await prompts.loadTemplates(options.promptTemplatesDir, baseSha)
+    } else {
+      warning(
+        'prompt_templates_dir is ignored: the event has no pull request base commit'
+      )
+    }
+  }
   return prompts
 }
 
@@ -109,7 +165,7 @@ export const runAction = async (): Promise<void> => {
   attachProcessGuards()
 
   const options = buildOptions()
-  const prompts = buildPrompts(options)
+  const prompts = await buildPrompts(options)
 
   const {lightBot, heavyBot} = createBots(options)
   options.print()
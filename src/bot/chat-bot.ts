@@ -4,14 +4,47 @@ import {info, setFailed, warning} from '@actions/core'
 
 import {AIProvider, ConversationState} from './providers/ai-provider'
 import {ProviderFactory, ProviderType} from './providers/provider-factory'
+import {CacheKey, ResponseCache} from './response-cache'
+import {PR_LEVEL_LABEL, UsageTracker} from './usage-tracker'
 import {Options} from '../config/options'
+import {RateLimiter} from '../utils/rate-limiter'
+
+// The summary and review bots talk to the same provider, so they share its
+// rate limit.
+const rateLimiters = new Map<ProviderType, RateLimiter>()
+
+const rateLimiterFor = (
+  providerType: ProviderType,
+  requestsPerMinute: number
+): RateLimiter => {
+  let limiter = rateLimiters.get(providerType)
+  if (limiter == null) {
+    limiter = new RateLimiter(requestsPerMinute)
+    rateLimiters.set(providerType, limiter)
+  }
+  return limiter
+}
 
 export class ChatBot {
   private readonly options: Options
   private readonly provider: AIProvider
+  private readonly usage: UsageTracker
+  private readonly cache: ResponseCache | null
+  private readonly providerType: ProviderType
+  private readonly model: string
 
-  constructor(options: Options, providerType: ProviderType, model: string) {
+  constructor(
+    options: Options,
+    providerType: ProviderType,
+    model: string,
+    usage: UsageTracker,
+    cache: ResponseCache | null = null
+  ) {
     this.options = options
+    this.usage = usage
+    this.cache = cache
+    this.providerType = providerType
+    this.model = model
     this.provider = ProviderFactory.createProvider(
       providerType,
       model,
@@ -19,18 +52,56 @@ export class ChatBot {
       options.language,
       {
         temperature: options.openaiModelTemperature,
-        timeout: options.openaiTimeoutMS,
-        retries: options.openaiRetries,
-        baseUrl: options.apiBaseUrl
+        timeout: options.modelTimeoutMS,
+        retries: options.modelRetries,
+        baseUrl: options.getBaseUrlForProvider(providerType),
+        rateLimiter: rateLimiterFor(
+          providerType,
+          options.modelRequestsPerMinute
+        )
       }
     )
   }
 
   async chat(
     message: string,
-    state: ConversationState
+    state: ConversationState,
+    label: string = PR_LEVEL_LABEL
   ): Promise<[string, ConversationState]> {
-    return this.provider.chat(message, state)
+    // Follow-up messages depend on provider-side conversation state, so only
+    // fresh requests are served from the cache. Hits cost nothing, so they are
+    // still served once the budget is spent.
+    const cacheKey = state.parentMessageId ? null : this.cacheKey(message)
+    if (this.cache && cacheKey) {
+      const cached = this.cache.get(cacheKey)
+      if (cached !== null) {
+        info(`response cache: hit for ${label}`)
+        return [cached, state]
+      }
+    }
+
+    if (this.usage.isOverBudget()) {
+      warning(`Skipped: cost budget reached, not sending request for ${label}`)
+      this.usage.recordSkipped()
+      return ['', state]
+    }
+
+    const result = await this.provider.chat(message, state)
+    // Providers report failures as an empty response. There are no tokens to
+    // count for those, and caching one would hide the failure on later runs.
+    if (result[0] === '') {
+      this.usage.recordFailed()
+      return result
+    }
+    this.usage.record(
+      label,
+      this.provider.getTokenCount(message),
+      this.provider.getTokenCount(result[0])
+    )
+    if (this.cache && cacheKey) {
+      this.cache.set(cacheKey, result[0])
+    }
+    return result
   }
 
   getTokenCount(text: string): number {
@@ -40,4 +111,19 @@ export class ChatBot {
   getModelInfo() {
     return this.provider.getModelInfo()
   }
+
+  getUsage(): UsageTracker {
+    return this.usage
+  }
+
+  private cacheKey(message: string): CacheKey {
+    return {
+      provider: this.providerType,
+      model: this.model,
+      temperature: this.options.openaiModelTemperature,
+      systemMessage: this.options.systemMessage,
+      language: this.options.language,
+      message
+    }
+  }
 }
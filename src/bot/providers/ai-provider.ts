@@ -1,3 +1,5 @@
+import {type RateLimiter} from '../../utils/rate-limiter'
+
 export interface AIProvider {
   chat(
     message: string,
@@ -28,4 +30,5 @@ export interface ProviderConfig {
   timeout: number
   retries: number
   baseUrl?: string
+  rateLimiter?: RateLimiter
 }
@@ -0,0 +1,153 @@
+import {info, warning} from '@actions/core'
+
+import {
+  AIProvider,
+  ConversationState,
+  ModelInfo,
+  ProviderConfig
+} from './ai-provider'
+import {postJSON} from '../../utils/http-client'
+
+const ANTHROPIC_API_VERSION = '2023-06-01'
+
+// Output limits by model prefix, first match wins. Newer models allow more,
+// but review responses never need more than 8192 tokens. Unknown models get
+// 4096, which every Claude model accepts.
+const MAX_OUTPUT_TOKENS: Array<[string, number]> = [
+  ['claude-3-5-', 8192],
+  ['claude-3-7-', 8192],
+  ['claude-3-', 4096],
+  ['claude-sonnet-4', 8192],
+  ['claude-opus-4', 8192],
+  ['claude-haiku-4', 8192]
+]
+const DEFAULT_MAX_OUTPUT_TOKENS = 4096
+
+interface AnthropicResponse {
+  id?: string
+  content?: Array<{type: string; text?: string}>
+  error?: {type: string; message: string}
+}
+
+export class AnthropicProvider implements AIProvider {
+  private readonly config: ProviderConfig
+  private readonly systemMessage: string
+
+  constructor(config: ProviderConfig, systemMessage: string, language: string) {
+    if (!config.apiKey) {
+      throw new Error('ANTHROPIC_API_KEY environment variable is not available')
+    }
+    this.config = config
+    this.systemMessage = this.composeSystemMessage(systemMessage, language)
+  }
+
+  async chat(
+    message: string,
+    state: ConversationState
+  ): Promise<[string, ConversationState]> {
+    if (!message.trim()) {
+      return ['', state]
+    }
+
+    try {
+      return await this.executeChat(message, state)
+    } catch (error: unknown) {
+      warning(`Failed to chat with Anthropic: ${error}`)
+      return ['', state]
+    }
+  }
+
+  getTokenCount(text: string): number {
+    // Claude uses its own tokenizer, approximate with character count
+    return Math.ceil(text.length / 4)
+  }
+
+  getModelInfo(): ModelInfo {
+    return {
+      name: this.config.model,
+      maxTokens: 200000,
+      responseTokens: this.getMaxOutputTokens(),
+      knowledgeCutOff: '2024-04-01'
+    }
+  }
+
+  private composeSystemMessage(
+    systemMessage: string,
+    language: string
+  ): string {
+    const currentDate = new Date().toISOString().split('T')[0]
+    const prefix = systemMessage ? `${systemMessage}\n` : ''
+
+    return `${prefix}Current date: ${currentDate}
+
+IMPORTANT: Entire response must be in the language with ISO code: ${language}
+`
+  }
+
+  private async executeChat(
+    message: string,
+    state: ConversationState
+  ): Promise<[string, ConversationState]> {
+    const startedAt = Date.now()
+
+    let response: AnthropicResponse
+    try {
+      response = await this.sendMessage(message)
+    } catch (error: unknown) {
+      info(`Anthropic response failed: ${error}`)
+      return ['', state]
+    }
+
+    const endedAt = Date.now()
+    info(`Anthropic response time: ${endedAt - startedAt} ms`)
+
+    const text = this.extractMessageText(response)
+    const nextState: ConversationState = {
+      parentMessageId: response.id,
+      conversationId: state.conversationId || `conv-${Date.now()}`,
+      provider: 'anthropic'
+    }
+
+    return [text, nextState]
+  }
+
+  private async sendMessage(message: string): Promise<AnthropicResponse> {
+    const baseUrl = this.config.baseUrl || 'https://api.anthropic.com/v1'
+    return await postJSON<AnthropicResponse>(
+      'Anthropic',
+      `${baseUrl}/messages`,
+      {
+        'x-api-key': this.config.apiKey,
+        'anthropic-version': ANTHROPIC_API_VERSION
+      },
+      {
+        model: this.config.model,
+        max_tokens: this.getMaxOutputTokens(),
+        temperature: this.config.temperature,
+        system: this.systemMessage,
+        messages: [{role: 'user', content: message}]
+      },
+      this.config,
+      body => body.error?.message
+    )
+  }
+
+  private extractMessageText(response: AnthropicResponse): string {
+    if (!response.content) {
+      warning('Anthropic response is null')
+      return ''
+    }
+
+    return response.content
+      .filter(block => block.type === 'text')
+      .map(block => block.text ?? '')
+      .join('')
+  }
+
+  private getMaxOutputTokens(): number {
+    const match = MAX_OUTPUT_TOKENS.find(([prefix]) =>
+      this.config.model.startsWith(prefix)
+    )
+    return match ? match[1] : DEFAULT_MAX_OUTPUT_TOKENS
+  }
+}
@@ -1,7 +1,4 @@
-import '../../utils/fetch-polyfill'
-import {info, setFailed, warning} from '@actions/core'
-import {GoogleGenerativeAI} from '@google/generative-ai'
-import pRetry from 'p-retry'
+import {info, warning} from '@actions/core'
 
 import {
   AIProvider,
@@ -9,13 +6,25 @@ import {
   ModelInfo,
   ProviderConfig
 } from './ai-provider'
+import {postJSON} from '../../utils/http-client'
+
+// Called over REST rather than through @google/generative-ai: SDK 0.2.1 takes
+// no AbortSignal and never clears its own timeout timer, so a slow request
+// either runs unbounded or keeps the process alive after the review is done.
+interface GeminiResponse {
+  candidates?: Array<{content?: {parts?: Array<{text?: string}>}}>
+  error?: {code: number; message: string}
+}
 
 export class GeminiProvider implements AIProvider {
-  private readonly model: any
   private readonly config: ProviderConfig
+  private readonly systemMessage: string
   private readonly generationConfig: any
 
   constructor(config: ProviderConfig, systemMessage: string, language: string) {
+    if (!config.apiKey) {
+      throw new Error('GEMINI_API_KEY environment variable is not available')
+    }
     this.config = config
     this.generationConfig = {
       temperature: Math.max(config.temperature, 0.1), // Ensure some creativity for finding issues
@@ -23,7 +32,7 @@ export class GeminiProvider implements AIProvider {
       topP: 0.9, // Higher for more diverse responses
       topK: 50 // Higher for more exploration
     }
-    this.model = this.createModel(systemMessage, language)
+    this.systemMessage = this.composeSystemMessage(systemMessage, language)
   }
 
   async chat(
@@ -56,21 +65,6 @@ export class GeminiProvider implements AIProvider {
     }
   }
 
-  private createModel(systemMessage: string, language: string): any {
-    if (!this.config.apiKey) {
-      throw new Error('GEMINI_API_KEY environment variable is not available')
-    }
-
-    const genAI = new GoogleGenerativeAI(this.config.apiKey)
-    const model = genAI.getGenerativeModel({
-      model: this.config.model,
-      generationConfig: this.generationConfig,
-      systemInstruction: this.composeSystemMessage(systemMessage, language)
-    })
-
-    return model
-  }
-
   private composeSystemMessage(
     systemMessage: string,
     language: string
@@ -100,11 +94,9 @@ Be concise, professional, and constructive in your feedback.`
   ): Promise<[string, ConversationState]> {
     const startedAt = Date.now()
 
-    let response
+    let response: GeminiResponse
     try {
-      response = await pRetry(() => this.model.generateContent(message), {
-        retries: this.config.retries
-      })
+      response = await this.generateContent(message)
     } catch (error: unknown) {
       info(`Gemini response failed: ${error}`)
       return ['', state]
@@ -123,19 +115,31 @@ Be concise, professional, and constructive in your feedback.`
     return [text, nextState]
   }
 
-  private extractMessageText(response: any): string {
-    if (!response || !response.response) {
+  private async generateContent(message: string): Promise<GeminiResponse> {
+    const baseUrl =
+      this.config.baseUrl || 'https://generativelanguage.googleapis.com/v1beta'
+    return await postJSON<GeminiResponse>(
+      'Gemini',
+      `${baseUrl}/models/${this.config.model}:generateContent`,
+      {'x-goog-api-key': this.config.apiKey},
+      {
+        systemInstruction: {parts: [{text: this.systemMessage}]},
+        contents: [{role: 'user', parts: [{text: message}]}],
+        generationConfig: this.generationConfig
+      },
+      this.config,
+      body => body.error?.message
+    )
+  }
+
+  private extractMessageText(response: GeminiResponse): string {
+    const parts = response.candidates?.[0]?.content?.parts
+    if (!parts) {
       warning('Gemini response is null')
       return ''
     }
 
-    try {
-      const text = response.response.text()
-      return text || ''
-    } catch (error) {
-      warning(`Failed to extract text from Gemini response: ${error}`)
-      return ''
-    }
+    return parts.map(part => part.text ?? '').join('')
   }
 
   private getMaxTokens(model: string): number {
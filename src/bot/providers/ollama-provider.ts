@@ -0,0 +1,123 @@
+import {info, warning} from '@actions/core'
+
+import {
+  AIProvider,
+  ConversationState,
+  ModelInfo,
+  ProviderConfig
+} from './ai-provider'
+import {postJSON} from '../../utils/http-client'
+
+interface OllamaResponse {
+  message?: {role: string; content: string}
+  error?: string
+}
+
+export class OllamaProvider implements AIProvider {
+  private readonly config: ProviderConfig
+  private readonly systemMessage: string
+
+  constructor(config: ProviderConfig, systemMessage: string, language: string) {
+    this.config = config
+    this.systemMessage = this.composeSystemMessage(systemMessage, language)
+  }
+
+  async chat(
+    message: string,
+    state: ConversationState
+  ): Promise<[string, ConversationState]> {
+    if (!message.trim()) {
+      return ['', state]
+    }
+
+    try {
+      return await this.executeChat(message, state)
+    } catch (error: unknown) {
+      warning(`Failed to chat with Ollama: ${error}`)
+      return ['', state]
+    }
+  }
+
+  getTokenCount(text: string): number {
+    // Local models ship their own tokenizers, approximate with character count
+    return Math.ceil(text.length / 4)
+  }
+
+  getModelInfo(): ModelInfo {
+    return {
+      name: this.config.model,
+      maxTokens: 8192,
+      responseTokens: 2048,
+      knowledgeCutOff: 'unknown'
+    }
+  }
+
+  private composeSystemMessage(
+    systemMessage: string,
+    language: string
+  ): string {
+    const currentDate = new Date().toISOString().split('T')[0]
+    const prefix = systemMessage ? `${systemMessage}\n` : ''
+
+    return `${prefix}Current date: ${currentDate}
+
+IMPORTANT: Entire response must be in the language with ISO code: ${language}
+`
+  }
+
+  private async executeChat(
+    message: string,
+    state: ConversationState
+  ): Promise<[string, ConversationState]> {
+    const startedAt = Date.now()
+
+    let response: OllamaResponse
+    try {
+      response = await this.sendMessage(message)
+    } catch (error: unknown) {
+      info(`Ollama response failed: ${error}`)
+      return ['', state]
+    }
+
+    const endedAt = Date.now()
+    info(`Ollama response time: ${endedAt - startedAt} ms`)
+
+    const text = this.extractMessageText(response)
+    const nextState: ConversationState = {
+      parentMessageId: `ollama-${Date.now()}`, // Ollama doesn't have message IDs
+      conversationId: state.conversationId || `conv-${Date.now()}`,
+      provider: 'ollama'
+    }
+
+    return [text, nextState]
+  }
+
+  private async sendMessage(message: string): Promise<OllamaResponse> {
+    const baseUrl = this.config.baseUrl || 'http://localhost:11434'
+    return await postJSON<OllamaResponse>(
+      'Ollama',
+      `${baseUrl}/api/chat`,
+      {},
+      {
+        model: this.config.model,
+        stream: false,
+        options: {temperature: this.config.temperature},
+        messages: [
+          {role: 'system', content: this.systemMessage},
+          {role: 'user', content: message}
+        ]
+      },
+      this.config,
+      body => body.error
+    )
+  }
+
+  private extractMessageText(response: OllamaResponse): string {
+    if (!response.message) {
+      warning('Ollama response is null')
+      return ''
+    }
+
+    return response.message.content ?? ''
+  }
+}
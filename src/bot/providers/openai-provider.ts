@@ -6,7 +6,6 @@ import {
   ChatMessage,
   SendMessageOptions
 } from 'chatgpt'
-import pRetry from 'p-retry'
 
 import {
   AIProvider,
@@ -15,6 +14,7 @@ import {
   ProviderConfig
 } from './ai-provider'
 import {TokenLimits} from '../../config/token-limits'
+import {withRetries} from '../../utils/http-client'
 
 export class OpenAIProvider implements AIProvider {
   private readonly client: ChatGPTAPI | null
@@ -116,11 +116,18 @@ IMPORTANT: Entire response must be in the language with ISO code: ${language}
 
     let response: ChatMessage | undefined
     try {
-      response = await pRetry(
+      response = await withRetries(
+        'OpenAI',
         () => this.client!.sendMessage(message, sendOptions),
-        {
-          retries: this.config.retries
-        }
+        this.config,
+        error => (error instanceof ChatGPTError ? error.statusCode : undefined),
+        // the SDK attaches the failed fetch Response as the cause
+        error =>
+          error instanceof ChatGPTError
+            ? (error.cause as Response | undefined)?.headers?.get?.(
+                'retry-after'
+              )
+            : undefined
       )
     } catch (error: unknown) {
       if (error instanceof ChatGPTError) {
@@ -1,10 +1,18 @@
 import {getInput} from '@actions/core'
 
 import {AIProvider, ProviderConfig} from './ai-provider'
+import {AnthropicProvider} from './anthropic-provider'
 import {GeminiProvider} from './gemini-provider'
+import {OllamaProvider} from './ollama-provider'
 import {OpenAIProvider} from './openai-provider'
+import {type RateLimiter} from '../../utils/rate-limiter'
 
-export type ProviderType = 'openai' | 'gemini' | 'auto'
+export type ProviderType =
+  | 'openai'
+  | 'gemini'
+  | 'anthropic'
+  | 'ollama'
+  | 'auto'
 
 export class ProviderFactory {
   static createProvider(
@@ -17,6 +25,7 @@ export class ProviderFactory {
       timeout: number
       retries: number
       baseUrl?: string
+      rateLimiter?: RateLimiter
     }
   ): AIProvider {
     const config: ProviderConfig = {
@@ -25,7 +34,8 @@ export class ProviderFactory {
       temperature: options.temperature,
       timeout: options.timeout,
       retries: options.retries,
-      baseUrl: options.baseUrl
+      baseUrl: options.baseUrl,
+      rateLimiter: options.rateLimiter
     }
 
     switch (providerType) {
@@ -33,6 +43,10 @@ export class ProviderFactory {
         return new OpenAIProvider(config, systemMessage, language)
       case 'gemini':
         return new GeminiProvider(config, systemMessage, language)
+      case 'anthropic':
+        return new AnthropicProvider(config, systemMessage, language)
+      case 'ollama':
+        return new OllamaProvider(config, systemMessage, language)
       case 'auto':
         return this.createAutoProvider(config, systemMessage, language)
       default:
@@ -46,6 +60,13 @@ export class ProviderFactory {
         return process.env.OPENAI_API_KEY || getInput('openai_api_key') || ''
       case 'gemini':
         return process.env.GEMINI_API_KEY || getInput('gemini_api_key') || ''
+      case 'anthropic':
+        return (
+          process.env.ANTHROPIC_API_KEY || getInput('anthropic_api_key') || ''
+        )
+      case 'ollama':
+        // Local Ollama servers do not require an API key
+        return ''
       case 'auto':
         // Auto-select based on available API keys only
         if (process.env.GEMINI_API_KEY || getInput('gemini_api_key')) {
@@ -54,6 +75,11 @@ export class ProviderFactory {
         if (process.env.OPENAI_API_KEY || getInput('openai_api_key')) {
           return process.env.OPENAI_API_KEY || getInput('openai_api_key') || ''
         }
+        if (process.env.ANTHROPIC_API_KEY || getInput('anthropic_api_key')) {
+          return (
+            process.env.ANTHROPIC_API_KEY || getInput('anthropic_api_key') || ''
+          )
+        }
         return ''
       default:
         throw new Error(`Unsupported provider type: ${providerType}`)
@@ -70,9 +96,11 @@ export class ProviderFactory {
       return new GeminiProvider(config, systemMessage, language)
     } else if (process.env.OPENAI_API_KEY || getInput('openai_api_key')) {
       return new OpenAIProvider(config, systemMessage, language)
+    } else if (process.env.ANTHROPIC_API_KEY || getInput('anthropic_api_key')) {
+      return new AnthropicProvider(config, systemMessage, language)
     } else {
       throw new Error(
-        'No AI provider API keys available. Please set OPENAI_API_KEY or GEMINI_API_KEY'
+        'No AI provider API keys available. Please set OPENAI_API_KEY, GEMINI_API_KEY or ANTHROPIC_API_KEY'
       )
     }
   }
@@ -88,6 +116,10 @@ export class ProviderFactory {
       providers.push('gemini')
     }
 
+    if (process.env.ANTHROPIC_API_KEY || getInput('anthropic_api_key')) {
+      providers.push('anthropic')
+    }
+
     if (providers.length > 0) {
       providers.push('auto')
     }
@@ -0,0 +1,83 @@
+import assert from 'node:assert/strict'
+import {existsSync, mkdtempSync, readdirSync, symlinkSync} from 'node:fs'
+import {tmpdir} from 'node:os'
+import {join} from 'node:path'
+import {afterEach, beforeEach, test} from 'node:test'
+
+import {type CacheKey, ResponseCache} from './response-cache'
+
+const key: CacheKey = {
+  provider: 'anthropic',
+  model: 'claude-sonnet-4-5',
+  temperature: 0,
+  systemMessage: '',
+  language: 'en-US',
+  message: 'review this diff'
+}
+
+let savedWorkspace: string | undefined
+let root: string
+let workspace: string
+
+beforeEach(() => {
+  savedWorkspace = process.env.GITHUB_WORKSPACE
+  root = mkdtempSync(join(tmpdir(), 'reval-cache-'))
+  workspace = join(root, 'workspace')
+})
+
+afterEach(() => {
+  if (savedWorkspace === undefined) {
+    delete process.env.GITHUB_WORKSPACE
+  } else {
+    process.env.GITHUB_WORKSPACE = savedWorkspace
+  }
+})
+
+// Writes an entry the way a pull request could, by caching it while the
+// directory is not yet inside the workspace.
+const seed = (dir: string, response: string): void => {
+  process.env.GITHUB_WORKSPACE = join(root, 'elsewhere')
+  new ResponseCache(dir).set(key, response)
+  process.env.GITHUB_WORKSPACE = workspace
+}
+
+test('serves entries from a directory outside the workspace', () => {
+  const dir = join(root, 'cache')
+  seed(dir, 'cached review')
+
+  assert.equal(new ResponseCache(dir).get(key), 'cached review')
+})
+
+test('never reads or writes a directory inside the workspace', () => {
+  const dir = join(workspace, '.reval-cache')
+  seed(dir, 'LGTM!')
+  const planted = readdirSync(dir)
+
+  const cache = new ResponseCache(dir)
+  assert.equal(cache.get(key), null)
+  cache.set({...key, message: 'another diff'}, 'real review')
+  assert.deepEqual(readdirSync(dir), planted)
+})
+
+test('treats the workspace itself as inside the workspace', () => {
+  seed(workspace, 'LGTM!')
+
+  assert.equal(new ResponseCache(workspace).get(key), null)
+})
+
+test('resolves symlinks that lead into the workspace', () => {
+  const dir = join(workspace, 'cache')
+  seed(dir, 'LGTM!')
+  const link = join(root, 'link')
+  symlinkSync(workspace, link)
+
+  assert.equal(new ResponseCache(join(link, 'cache')).get(key), null)
+})
+
+test('does not create a directory inside the workspace', () => {
+  process.env.GITHUB_WORKSPACE = workspace
+  const dir = join(workspace, 'new-cache')
+
+  new ResponseCache(dir).set(key, 'real review')
+  assert.equal(existsSync(dir), false)
+})
@@ -0,0 +1,102 @@
+import {warning} from '@actions/core'
+import {createHash} from 'crypto'
+import {
+  existsSync,
+  mkdirSync,
+  readFileSync,
+  realpathSync,
+  writeFileSync
+} from 'fs'
+import {basename, dirname, isAbsolute, join, relative, resolve, sep} from 'path'
+
+export interface CacheKey {
+  provider: string
+  model: string
+  temperature: number
+  systemMessage: string
+  language: string
+  message: string
+}
+
+interface CacheEntry {
+  model: string
+  response: string
+}
+
+// Resolves symlinks in the longest existing prefix of path, so a directory
+// that has not been created yet is judged by where it will really live.
+const realPath = (path: string): string => {
+  const absolute = resolve(path)
+  try {
+    return realpathSync(absolute)
+  } catch (e) {
+    const parent = dirname(absolute)
+    return parent === absolute
+      ? absolute
+      : join(realPath(parent), basename(absolute))
+  }
+}
+
+const isWithin = (path: string, root: string): boolean => {
+  const rel = relative(root, path)
+  return (
+    rel === '' ||
+    (rel !== '..' && !rel.startsWith(`..${sep}`) && !isAbsolute(rel))
+  )
+}
+
+// Stores model responses on disk so repeated runs over the same diffs do not
+// re-send identical prompts. Only stateless requests are cacheable.
+export class ResponseCache {
+  private readonly enabled: boolean
+
+  constructor(private readonly dir: string) {
+    // Files in the checkout come from the pull request, which could otherwise
+    // plant cached answers (an "LGTM" for every prompt) for its own review.
+    const workspace = process.env.GITHUB_WORKSPACE
+    this.enabled = !(workspace && isWithin(realPath(dir), realPath(workspace)))
+    if (!this.enabled) {
+      warning(
+        `response cache: ${dir} is inside the workspace, caching is disabled; use a directory outside it, for example under \${{ runner.temp }}`
+      )
+    }
+  }
+
+  get(key: CacheKey): string | null {
+    if (!this.enabled) {
+      return null
+    }
+
+    const path = this.pathFor(key)
+    if (!existsSync(path)) {
+      return null
+    }
+
+    try {
+      const entry = JSON.parse(readFileSync(path, 'utf8')) as CacheEntry
+      return entry.response
+    } catch (error: unknown) {
+      warning(`response cache: failed to read ${path}: ${error}`)
+      return null
+    }
+  }
+
+  set(key: CacheKey, response: string): void {
+    if (!this.enabled || response === '') {
+      return
+    }
+
+    const entry: CacheEntry = {model: key.model, response}
+    try {
+      mkdirSync(this.dir, {recursive: true})
+      writeFileSync(this.pathFor(key), JSON.stringify(entry))
+    } catch (error: unknown) {
+      warning(`response cache: failed to write to ${this.dir}: ${error}`)
+    }
+  }
+
+  private pathFor(key: CacheKey): string {
+    const hash = createHash('sha256').update(JSON.stringify(key)).digest('hex')
+    return join(this.dir, `${hash}.json`)
+  }
+}
@@ -0,0 +1,125 @@
+export interface TokenUsage {
+  requests: number
+  promptTokens: number
+  completionTokens: number
+}
+
+export interface UsagePricing {
+  promptCostPer1K: number
+  completionCostPer1K: number
+  maxCost: number
+}
+
+export const PR_LEVEL_LABEL = '(pull request)'
+
+export class UsageTracker {
+  private readonly usageByLabel = new Map<string, TokenUsage>()
+  private skippedRequests = 0
+  private failedRequests = 0
+
+  constructor(private readonly pricing: UsagePricing) {}
+
+  record(label: string, promptTokens: number, completionTokens: number): void {
+    const usage = this.usageByLabel.get(label) ?? {
+      requests: 0,
+      promptTokens: 0,
+      completionTokens: 0
+    }
+    usage.requests += 1
+    usage.promptTokens += promptTokens
+    usage.completionTokens += completionTokens
+    this.usageByLabel.set(label, usage)
+  }
+
+  recordSkipped(): void {
+    this.skippedRequests += 1
+  }
+
+  recordFailed(): void {
+    this.failedRequests += 1
+  }
+
+  totals(): TokenUsage {
+    const totals: TokenUsage = {
+      requests: 0,
+      promptTokens: 0,
+      completionTokens: 0
+    }
+    for (const usage of this.usageByLabel.values()) {
+      totals.requests += usage.requests
+      totals.promptTokens += usage.promptTokens
+      totals.completionTokens += usage.completionTokens
+    }
+    return totals
+  }
+
+  cost(usage: TokenUsage): number {
+    return (
+      (usage.promptTokens / 1000) * this.pricing.promptCostPer1K +
+      (usage.completionTokens / 1000) * this.pricing.completionCostPer1K
+    )
+  }
+
+  isOverBudget(): boolean {
+    return (
+      this.pricing.maxCost > 0 &&
+      this.cost(this.totals()) >= this.pricing.maxCost
+    )
+  }
+
+  string(): string {
+    const totals = this.totals()
+    return `requests=${totals.requests}, prompt_tokens=${
+      totals.promptTokens
+    }, completion_tokens=${totals.completionTokens}, cost=$${this.cost(
+      totals
+    ).toFixed(4)}, skipped_over_budget=${this.skippedRequests}, failed=${
+      this.failedRequests
+    }`
+  }
+
+  renderMarkdown(): string {
+    const totals = this.totals()
+    if (totals.requests === 0) {
+      return ''
+    }
+
+    const rows = [...this.usageByLabel.entries()]
+      .sort(([a], [b]) => a.localeCompare(b))
+      .map(
+        ([label, usage]) =>
+          `| ${label} | ${usage.requests} | ${usage.promptTokens} | ${
+            usage.completionTokens
+          } | $${this.cost(usage).toFixed(4)} |`
+      )
+
+    return `<details>
+<summary>Token usage (~${
+      totals.promptTokens + totals.completionTokens
+    } tokens, est. $${this.cost(totals).toFixed(4)})</summary>
+
+| File | Requests | Prompt tokens | Completion tokens | Est. cost |
+|------|----------|---------------|-------------------|-----------|
+${rows.join('\n')}
+| **Total** | ${totals.requests} | ${totals.promptTokens} | ${
+      totals.completionTokens
+    } | $${this.cost(totals).toFixed(4)} |
+${
+  this.skippedRequests > 0
+    ? `
+Budget of $${this.pricing.maxCost} reached: ${this.skippedRequests} requests were skipped.
+`
+    : ''
+}${
+  this.failedRequests > 0
+    ? `
+${this.failedRequests} requests failed and are not counted.
+`
+    : ''
+}
+Token counts and costs are approximate: they are estimated locally, not taken from the provider's bill.
+
+</details>
+`
+  }
+}
@@ -2,6 +2,7 @@ import {info} from '@actions/core'
 import {minimatch} from 'minimatch'
 
 import {TokenLimits} from './token-limits'
+import type {ModelInfo} from '../bot/providers/ai-provider'
 import type {ProviderType} from '../bot/providers/provider-factory'
 
 export interface OptionsInit {
@@ -13,9 +14,28 @@ export interface OptionsInit {
   model: string
   openaiModel: string
   geminiModel: string
+  anthropicModel?: string
+  ollamaModel?: string
+  ollamaBaseUrl?: string
   enableApplySuggestions?: string
   maxSuggestionLines?: string
   maxSuggestionsPerFile?: string
+  notifyWebhookUrl?: string
+  notifySlackWebhookUrl?: string
+  notifyMinReviewComments?: string
+  failOnReviewComments?: string
+  promptCostPer1K?: string
+  completionCostPer1K?: string
+  maxCost?: string
+  promptTemplatesDir?: string
+  pathFilters?: string[]
+  reviewGeneratedFiles?: string
+  responseCacheDir?: string
+  modelRetries?: string
+  modelTimeoutMS?: string
+  modelConcurrencyLimit?: string
+  modelRequestsPerMinute?: string
+  githubConcurrencyLimit?: string
 }
 
 export class Options {
@@ -28,9 +48,21 @@ export class Options {
   model: string
   openaiModel: string
   geminiModel: string
+  anthropicModel: string
+  ollamaModel: string
   enableApplySuggestions: boolean
   maxSuggestionLines: number
   maxSuggestionsPerFile: number
+  notifyWebhookUrl: string
+  notifySlackWebhookUrl: string
+  notifyMinReviewComments: number
+  failOnReviewComments: number
+  promptCostPer1K: number
+  completionCostPer1K: number
+  maxCost: number
+  promptTemplatesDir: string
+  reviewGeneratedFiles: boolean
+  responseCacheDir: string
 
   debug = false
   disableReview = false
@@ -40,11 +72,13 @@ export class Options {
   reviewCommentLGTM = false
   pathFilters: PathFilter
   openaiModelTemperature = 0.0
-  openaiRetries = 3
-  openaiTimeoutMS = 120000
-  openaiConcurrencyLimit = 6
-  githubConcurrencyLimit = 6
+  modelRetries: number
+  modelTimeoutMS: number
+  modelConcurrencyLimit: number
+  modelRequestsPerMinute: number
+  githubConcurrencyLimit: number
   apiBaseUrl = 'https://api.openai.com/v1'
+  ollamaBaseUrl = 'http://localhost:11434'
 
   lightTokenLimits: TokenLimits
   heavyTokenLimits: TokenLimits
@@ -61,6 +95,12 @@ export class Options {
       sharedModel || init.openaiModel?.trim() || 'gpt-3.5-turbo'
     this.geminiModel =
       sharedModel || init.geminiModel?.trim() || 'gemini-2.5-flash'
+    this.anthropicModel =
+      sharedModel || init.anthropicModel?.trim() || 'claude-sonnet-4-5'
+    this.ollamaModel = sharedModel || init.ollamaModel?.trim() || 'llama3.1'
+    if (init.ollamaBaseUrl?.trim()) {
+      this.ollamaBaseUrl = init.ollamaBaseUrl.trim()
+    }
 
     this.model = this.getModelForProvider(this.aiProvider as ProviderType)
 
@@ -78,8 +118,46 @@ export class Options {
       5,
       0
     )
+    this.notifyWebhookUrl = init.notifyWebhookUrl?.trim() ?? ''
+    this.notifySlackWebhookUrl = init.notifySlackWebhookUrl?.trim() ?? ''
+    this.notifyMinReviewComments = parseIntWithDefault(
+      init.notifyMinReviewComments,
+      1,
+      0
+    )
+    this.failOnReviewComments = parseIntWithDefault(
+      init.failOnReviewComments,
+      0,
+      0
+    )
+    this.promptCostPer1K = parseFloatWithDefault(init.promptCostPer1K, 0)
+    this.completionCostPer1K = parseFloatWithDefault(
+      init.completionCostPer1K,
+      0
+    )
+    this.maxCost = parseFloatWithDefault(init.maxCost, 0)
+    this.promptTemplatesDir = init.promptTemplatesDir?.trim() ?? ''
+    this.reviewGeneratedFiles = parseBoolean(init.reviewGeneratedFiles, false)
+    this.responseCacheDir = init.responseCacheDir?.trim() ?? ''
+    this.modelRetries = parseIntWithDefault(init.modelRetries, 3, 0)
+    this.modelTimeoutMS = parseIntWithDefault(init.modelTimeoutMS, 120000, 1)
+    this.modelConcurrencyLimit = parseIntWithDefault(
+      init.modelConcurrencyLimit,
+      6,
+      1
+    )
+    this.modelRequestsPerMinute = parseIntWithDefault(
+      init.modelRequestsPerMinute,
+      0,
+      0
+    )
+    this.githubConcurrencyLimit = parseIntWithDefault(
+      init.githubConcurrencyLimit,
+      6,
+      1
+    )
 
-    this.pathFilters = new PathFilter(null)
+    this.pathFilters = new PathFilter(init.pathFilters ?? null)
     this.lightTokenLimits = new TokenLimits(this.model)
     this.heavyTokenLimits = new TokenLimits(this.model)
   }
@@ -92,22 +170,36 @@ export class Options {
     info(`review_simple_changes: ${this.reviewSimpleChanges}`)
     info(`review_comment_lgtm: ${this.reviewCommentLGTM}`)
     info(`path_filters: ${this.pathFilters}`)
+    info(`review_generated_files: ${this.reviewGeneratedFiles}`)
     info(`system_message: ${this.systemMessage}`)
     info(`ai_provider: ${this.aiProvider}`)
     info(`selected_model: ${this.model}`)
     info(`openai_model: ${this.openaiModel}`)
     info(`gemini_model: ${this.geminiModel}`)
+    info(`anthropic_model: ${this.anthropicModel}`)
+    info(`ollama_model: ${this.ollamaModel}`)
     info(`enable_apply_suggestions: ${this.enableApplySuggestions}`)
     info(`max_suggestion_lines: ${this.maxSuggestionLines}`)
     info(`max_suggestions_per_file: ${this.maxSuggestionsPerFile}`)
+    info(`notify_webhook: ${this.notifyWebhookUrl !== ''}`)
+    info(`notify_slack_webhook: ${this.notifySlackWebhookUrl !== ''}`)
+    info(`notify_min_review_comments: ${this.notifyMinReviewComments}`)
+    info(`fail_on_review_comments: ${this.failOnReviewComments}`)
+    info(`prompt_cost_per_1k_tokens: ${this.promptCostPer1K}`)
+    info(`completion_cost_per_1k_tokens: ${this.completionCostPer1K}`)
+    info(`max_cost: ${this.maxCost}`)
+    info(`prompt_templates_dir: ${this.promptTemplatesDir}`)
+    info(`response_cache_dir: ${this.responseCacheDir}`)
     info(`openai_model_temperature: ${this.openaiModelTemperature}`)
-    info(`openai_retries: ${this.openaiRetries}`)
-    info(`openai_timeout_ms: ${this.openaiTimeoutMS}`)
-    info(`openai_concurrency_limit: ${this.openaiConcurrencyLimit}`)
+    info(`model_retries: ${this.modelRetries}`)
+    info(`model_timeout_ms: ${this.modelTimeoutMS}`)
+    info(`model_concurrency_limit: ${this.modelConcurrencyLimit}`)
+    info(`model_requests_per_minute: ${this.modelRequestsPerMinute}`)
     info(`github_concurrency_limit: ${this.githubConcurrencyLimit}`)
     info(`summary_token_limits: ${this.lightTokenLimits.string()}`)
     info(`review_token_limits: ${this.heavyTokenLimits.string()}`)
     info(`api_base_url: ${this.apiBaseUrl}`)
+    info(`ollama_base_url: ${this.ollamaBaseUrl}`)
     info(`language: ${this.language}`)
   }
 
@@ -123,17 +215,37 @@ export class Options {
         return this.geminiModel || this.openaiModel
       case 'openai':
         return this.openaiModel || this.geminiModel
+      case 'anthropic':
+        return this.anthropicModel
+      case 'ollama':
+        return this.ollamaModel
       case 'auto':
       default:
         return this.geminiModel || this.openaiModel
     }
   }
 
+  getBaseUrlForProvider(providerType: ProviderType): string | undefined {
+    switch (providerType) {
+      case 'openai':
+        return this.apiBaseUrl
+      case 'ollama':
+        return this.ollamaBaseUrl
+      default:
+        return undefined
+    }
+  }
+
   updateSelectedModel(model: string): void {
     this.model = model
     this.lightTokenLimits = new TokenLimits(model)
     this.heavyTokenLimits = new TokenLimits(model)
   }
+
+  updateTokenLimits(modelInfo: ModelInfo): void {
+    this.lightTokenLimits = TokenLimits.fromModelInfo(modelInfo)
+    this.heavyTokenLimits = TokenLimits.fromModelInfo(modelInfo)
+  }
 }
 
 function parseBoolean(value: string | undefined, defaultValue: boolean): boolean {
@@ -161,6 +273,20 @@ function parseIntWithDefault(
   return Math.max(parsed, minValue)
 }
 
+function parseFloatWithDefault(
+  value: string | undefined,
+  defaultValue: number
+): number {
+  if (value == null || value.trim() === '') {
+    return defaultValue
+  }
+  const parsed = parseFloat(value)
+  if (!Number.isFinite(parsed) || parsed < 0) {
+    return defaultValue
+  }
+  return parsed
+}
+
 export class PathFilter {
   private readonly rules: Array<[string, boolean]>
 
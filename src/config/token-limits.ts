@@ -1,5 +1,9 @@
+import type {ModelInfo} from '../bot/providers/ai-provider'
 import {getTokenCount as calculateTokenCount} from '../review/tokenizer'
 
+// provide some margin for the request tokens
+const REQUEST_MARGIN_TOKENS = 100
+
 export class TokenLimits {
   maxTokens: number
   requestTokens: number
@@ -21,8 +25,20 @@ export class TokenLimits {
       this.maxTokens = 4000
       this.responseTokens = 1000
     }
-    // provide some margin for the request tokens
-    this.requestTokens = this.maxTokens - this.responseTokens - 100
+    this.requestTokens =
+      this.maxTokens - this.responseTokens - REQUEST_MARGIN_TOKENS
+  }
+
+  // The table above only knows OpenAI models; every provider reports the
+  // window of the model it is configured with.
+  static fromModelInfo(modelInfo: ModelInfo): TokenLimits {
+    const limits = new TokenLimits(modelInfo.name)
+    limits.maxTokens = modelInfo.maxTokens
+    limits.responseTokens = modelInfo.responseTokens
+    limits.knowledgeCutOff = modelInfo.knowledgeCutOff
+    limits.requestTokens =
+      limits.maxTokens - limits.responseTokens - REQUEST_MARGIN_TOKENS
+    return limits
   }
 
   string(): string {
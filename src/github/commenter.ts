@@ -438,23 +438,8 @@ ${chain}
     }
 
     const allComments: any[] = []
-    let page = 1
     try {
-      for (;;) {
-        const {data: comments} = await octokit.pulls.listReviewComments({
-          owner: repo.owner,
-          repo: repo.repo,
-          pull_number: target,
-          page,
-          per_page: 100
-        })
-        allComments.push(...comments)
-        page++
-        if (!comments || comments.length < 100) {
-          break
-        }
-      }
-
+      await this.fetchReviewComments(target, allComments)
       this.reviewCommentsCache[target] = allComments
       return allComments
     } catch (e) {
@@ -463,6 +448,45 @@ ${chain}
     }
   }
 
+  // Appends every review comment on the pull request to allComments, page by
+  // page, and throws on the first failed request.
+  private async fetchReviewComments(target: number, allComments: any[]) {
+    let page = 1
+    for (;;) {
+      const {data: comments} = await octokit.pulls.listReviewComments({
+        owner: repo.owner,
+        repo: repo.repo,
+        pull_number: target,
+        page,
+        per_page: 100
+      })
+      allComments.push(...comments)
+      page++
+      if (!comments || comments.length < 100) {
+        break
+      }
+    }
+  }
+
+  // Counts our top-level review comments that still apply to the current diff.
+  // Outdated comments (no position in the latest diff) and LGTM notes are
+  // not counted, so the total clears as flagged lines are changed or deleted.
+  // Unlike listReviewComments this throws when the listing fails, since a
+  // partial count could let a pull request with open comments pass.
+  async countOutstandingReviewComments(target: number): Promise<number> {
+    // skip the cache, its listing predates the review submitted in this run
+    const comments: any[] = []
+    await this.fetchReviewComments(target, comments)
+    this.reviewCommentsCache[target] = comments
+    return comments.filter(
+      (c: any) =>
+        !c.in_reply_to_id &&
+        c.position != null &&
+        (c.body.includes(COMMENT_TAG) || c.body.includes(COMMENT_REPLY_TAG)) &&
+        !c.body.includes('LGTM')
+    ).length
+  }
+
   async create(body: string, target: number) {
     try {
       // get comment ID from the response
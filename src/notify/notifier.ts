@@ -0,0 +1,104 @@
+import {info, warning} from '@actions/core'
+
+import {fetchWithTimeout} from '../utils/http-client'
+
+// Notifications are best effort and must not hold up the rest of the run.
+const NOTIFY_TIMEOUT_MS = 10000
+
+export interface ReviewSummary {
+  repository: string
+  pullRequestNumber: number
+  pullRequestTitle: string
+  pullRequestUrl: string
+  filesReviewed: number
+  reviewComments: number
+  lgtmComments: number
+  failedFiles: string[]
+}
+
+export interface NotifierConfig {
+  webhookUrl: string
+  slackWebhookUrl: string
+  minReviewComments: number
+}
+
+export class ReviewNotifier {
+  constructor(private readonly config: NotifierConfig) {}
+
+  isEnabled(): boolean {
+    return this.config.webhookUrl !== '' || this.config.slackWebhookUrl !== ''
+  }
+
+  async notify(summary: ReviewSummary): Promise<void> {
+    if (!this.isEnabled()) {
+      return
+    }
+
+    if (summary.reviewComments < this.config.minReviewComments) {
+      info(
+        `notify: ${summary.reviewComments} review comments below threshold ${this.config.minReviewComments}, skipping`
+      )
+      return
+    }
+
+    if (this.config.webhookUrl !== '') {
+      await this.post('webhook', this.config.webhookUrl, {
+        event: 'review_completed',
+        ...summary
+      })
+    }
+
+    if (this.config.slackWebhookUrl !== '') {
+      await this.post('slack', this.config.slackWebhookUrl, {
+        text: this.formatSlackMessage(summary)
+      })
+    }
+  }
+
+  private formatSlackMessage(summary: ReviewSummary): string {
+    let text = `*Reval review* for <${summary.pullRequestUrl}|${
+      summary.repository
+    }#${summary.pullRequestNumber}>: ${escapeSlack(summary.pullRequestTitle)}
+• Files reviewed: ${summary.filesReviewed}
+• Review comments: ${summary.reviewComments}
+• LGTM: ${summary.lgtmComments}`
+    if (summary.failedFiles.length > 0) {
+      text += `\n• Files not reviewed due to errors: ${summary.failedFiles.length}`
+    }
+    return text
+  }
+
+  private async post(
+    sink: string,
+    url: string,
+    payload: Record<string, unknown>
+  ): Promise<void> {
+    try {
+      const res = await fetchWithTimeout(
+        url,
+        {
+          method: 'POST',
+          headers: {'content-type': 'application/json'},
+          body: JSON.stringify(payload)
+        },
+        NOTIFY_TIMEOUT_MS,
+        async response => {
+          await response.text()
+          return response
+        }
+      )
+      if (!res.ok) {
+        warning(`notify: ${sink} returned ${res.status} ${res.statusText}`)
+        return
+      }
+      info(`notify: sent review summary to ${sink}`)
+    } catch (error: unknown) {
+      warning(`notify: failed to send review summary to ${sink}: ${error}`)
+    }
+  }
+}
+
+// Slack treats &, < and > as control characters in message text.
+function escapeSlack(text: string): string {
+  return text.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;')
+}
@@ -1,5 +1,38 @@
+import {info, warning} from '@actions/core'
+// eslint-disable-next-line camelcase
+import {context as github_context} from '@actions/github'
+import {posix} from 'path'
+
+import {octokit} from '../github/octokit'
 import {type Inputs} from '../shared/inputs'
 
+// eslint-disable-next-line camelcase
+const context = github_context
+const repo = context.repo
+
+type TemplateName =
+  | 'summarizeFileDiff'
+  | 'triageFileDiff'
+  | 'summarizeChangesets'
+  | 'summarizePrefix'
+  | 'summarizeShort'
+  | 'reviewFileDiff'
+  | 'comment'
+  | 'ruleTaxonomy'
+
+// Files that may override the built-in templates, looked up in the
+// directory given by the prompt_templates_dir input.
+const TEMPLATE_FILES: Record<TemplateName, string> = {
+  summarizeFileDiff: 'summarize_file_diff.md',
+  triageFileDiff: 'triage_file_diff.md',
+  summarizeChangesets: 'summarize_changesets.md',
+  summarizePrefix: 'summarize_prefix.md',
+  summarizeShort: 'summarize_short.md',
+  reviewFileDiff: 'review_file_diff.md',
+  comment: 'comment.md',
+  ruleTaxonomy: 'rule_taxonomy.md'
+}
+
 export class PromptLibrary {
   summarize: string = ''
   summarizeReleaseNotes: string = ''
@@ -244,6 +277,77 @@ $comment
 \`\`\`
 `
 
+  // Bug categories for $rule_taxonomy, matching the categories used to
+  // annotate the fixtures under tests/. The built-in prompts do not use it.
+  ruleTaxonomy = `- \`race\`: shared state accessed concurrently without synchronization
+- \`atomicity\`: related reads or writes that must happen together but can interleave
+- \`deadlock\`: goroutines, threads or wait groups that can block forever
+- \`channel\`: channel sends, receives or closes that can panic, block or leak
+- \`leak\`: files, connections, goroutines or other resources that are never released
+- \`timer-leak\`: timers that are never stopped
+- \`ticker-leak\`: tickers that are never stopped
+- \`sleep-sync\`: sleeps used in place of synchronization
+- \`defer-in-loop\`: defers that pile up until the enclosing function returns
+- \`loop-capture\`: closures that capture a loop variable
+- \`mutable-default\`: mutable default arguments shared between calls
+- \`bare-except\`: exception handlers that swallow every error
+- \`await-in-loop\`: awaits in a loop that could run concurrently
+- \`unhandled-rejection\`: promises whose rejections are never handled
+`
+
+  // Overrides are read at the pull request's base commit, not from the
+  // checked-out head, so a pull request cannot rewrite the prompts that are
+  // used to review it.
+  async loadTemplates(dir: string, ref: string): Promise<void> {
+    const root = posix.normalize(dir).replace(/^\.?\/+|\/+$/g, '')
+    let entries: Array<{name: string; path: string}>
+    try {
+      const {data} = await octokit.repos.getContent({
+        owner: repo.owner,
+        repo: repo.repo,
+        path: root === '.' ? '' : root,
+        ref
+      })
+      if (!Array.isArray(data)) {
+        warning(`prompt_templates_dir ${dir} is not a directory at ${ref}`)
+        return
+      }
+      entries = data
+    } catch (error: any) {
+      warning(
+        error?.status === 404
+          ? `prompt_templates_dir ${dir} does not exist at ${ref}, using the built-in prompts`
+          : `prompt_templates_dir ${dir} could not be read at ${ref}, using the built-in prompts: ${error}`
+      )
+      return
+    }
+
+    for (const [name, file] of Object.entries(TEMPLATE_FILES)) {
+      const entry = entries.find(e => e.name === file)
+      if (entry == null) {
+        continue
+      }
+      try {
+        const {data} = await octokit.repos.getContent({
+          owner: repo.owner,
+          repo: repo.repo,
+          path: entry.path,
+          ref
+        })
+        if (Array.isArray(data) || data.type !== 'file' || !data.content) {
+          continue
+        }
+        this[name as TemplateName] = Buffer.from(
+          data.content,
+          'base64'
+        ).toString()
+        info(`prompt template ${name} loaded from ${entry.path} at ${ref}`)
+      } catch (error: any) {
+        warning(`Failed to read prompt template ${entry.path}: ${error}`)
+      }
+    }
+  }
+
   renderSummarizeFileDiff(
     inputs: Inputs,
     reviewSimpleChanges: boolean
@@ -285,6 +389,8 @@ $comment
   }
 
   private renderTemplate(template: string, inputs: Inputs): string {
-    return inputs.render(template)
+    const withTaxonomy = inputs.clone()
+    withTaxonomy.ruleTaxonomy = this.ruleTaxonomy
+    return withTaxonomy.render(template)
   }
 }
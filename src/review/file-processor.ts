@@ -1,4 +1,4 @@
-import {warning} from '@actions/core'
+import {info, warning} from '@actions/core'
 // eslint-disable-next-line camelcase
 import {context as github_context} from '@actions/github'
 
@@ -20,6 +20,46 @@ type FileEntry = {
 
 type PatchTuple = [number, number, string]
 
+type FileChange = [string, string, string, PatchTuple[]]
+
+// Go's convention for generated files, also used by many other generators.
+const GENERATED_HEADER = /^\/\/ Code generated .* DO NOT EDIT\.\r?$/
+
+const GENERATED_FILE_SUFFIXES = ['.pb.go', '.pb.gw.go', '_mock.go', '.gen.go']
+
+// Like go/ast.IsGenerated, the header only counts in the comments before the
+// first line of code (the package clause in Go), not anywhere in the file.
+const hasGeneratedHeader = (fileContent: string): boolean => {
+  let inBlockComment = false
+  for (const line of fileContent.split('\n')) {
+    const trimmed = line.trim()
+    if (inBlockComment) {
+      inBlockComment = !trimmed.includes('*/')
+      continue
+    }
+    if (GENERATED_HEADER.test(line)) {
+      return true
+    }
+    if (trimmed.startsWith('/*')) {
+      inBlockComment = !trimmed.includes('*/', 2)
+      continue
+    }
+    if (trimmed !== '' && !trimmed.startsWith('//')) {
+      return false
+    }
+  }
+  return false
+}
+
+// fileContent is the file as of the pull request head, so removing the header
+// from a formerly generated file gets the change reviewed.
+export const isGeneratedFile = (
+  filename: string,
+  fileContent: string
+): boolean =>
+  GENERATED_FILE_SUFFIXES.some(suffix => filename.endsWith(suffix)) ||
+  hasGeneratedHeader(fileContent)
+
 export interface FileChangesResult {
   filesAndChanges: Array<[string, string, string, PatchTuple[]]>
   ignoredFiles: FileEntry[]
@@ -89,6 +129,52 @@ export class FileProcessor {
     return {selected, ignored}
   }
 
+  async filterGeneratedFiles(
+    filesAndChanges: FileChange[],
+    pullRequest: PullRequest
+  ): Promise<{
+    selected: FileChange[]
+    generated: string[]
+  }> {
+    if (this.options.reviewGeneratedFiles) {
+      return {selected: filesAndChanges, generated: []}
+    }
+
+    const flags = await Promise.all(
+      filesAndChanges.map(([filename, baseContent]) =>
+        this.githubConcurrencyLimit(async () => {
+          let headContent = baseContent
+          try {
+            headContent =
+              (await this.fetchFileContent(filename, pullRequest.head.sha)) ??
+              baseContent
+          } catch (error: any) {
+            // a file deleted by the pull request is judged by its base content
+            if (error?.status !== 404) {
+              warning(`Failed to get head contents of ${filename}: ${error}`)
+            }
+          }
+          return isGeneratedFile(filename, headContent)
+        })
+      )
+    )
+
+    const selected: FileChange[] = []
+    const generated: string[] = []
+
+    filesAndChanges.forEach((change, i) => {
+      const [filename] = change
+      if (flags[i]) {
+        info(`skipping generated file: ${filename}`)
+        generated.push(filename)
+      } else {
+        selected.push(change)
+      }
+    })
+
+    return {selected, generated}
+  }
+
   async buildFileChanges(
     files: FileEntry[],
     pullRequest: PullRequest
@@ -113,17 +199,9 @@ export class FileProcessor {
   ): Promise<[string, string, string, PatchTuple[]] | null> {
     let fileContent = ''
     try {
-      const contents = await octokit.repos.getContent({
-        owner: repo.owner,
-        repo: repo.repo,
-        path: file.filename,
-        ref: pullRequest.base.sha
-      })
-      if (contents.data != null && !Array.isArray(contents.data)) {
-        if (contents.data.type === 'file' && contents.data.content != null) {
-          fileContent = Buffer.from(contents.data.content, 'base64').toString()
-        }
-      }
+      fileContent =
+        (await this.fetchFileContent(file.filename, pullRequest.base.sha)) ??
+        ''
     } catch (error: any) {
       warning(
         `Failed to get file contents: ${
@@ -168,6 +246,27 @@ ${hunks.oldHunk}
 
     return [file.filename, fileContent, fileDiff, patches]
   }
+
+  private async fetchFileContent(
+    path: string,
+    ref: string
+  ): Promise<string | null> {
+    const contents = await octokit.repos.getContent({
+      owner: repo.owner,
+      repo: repo.repo,
+      path,
+      ref
+    })
+    if (
+      contents.data == null ||
+      Array.isArray(contents.data) ||
+      contents.data.type !== 'file' ||
+      contents.data.content == null
+    ) {
+      return null
+    }
+    return Buffer.from(contents.data.content, 'base64').toString()
+  }
 }
 
 const splitPatch = (patch: string | null | undefined): string[] => {
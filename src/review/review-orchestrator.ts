@@ -1,4 +1,4 @@
-import {error, info, warning} from '@actions/core'
+import {info, setFailed, warning} from '@actions/core'
 // eslint-disable-next-line camelcase
 import {context as github_context} from '@actions/github'
 import pLimit from 'p-limit'
@@ -11,6 +11,7 @@ import {
   SHORT_SUMMARY_START_TAG,
   SUMMARIZE_TAG
 } from '../github/comment-tags'
+import {ReviewNotifier, type ReviewSummary} from '../notify/notifier'
 import {Inputs} from '../shared/inputs'
 import {type Options} from '../config/options'
 import {type PromptLibrary} from '../prompts/templates'
@@ -38,7 +39,60 @@ export class ReviewOrchestrator {
     prompts: PromptLibrary
   ): Promise<void> {
     const commenter = new Commenter()
-    const openaiConcurrencyLimit = pLimit(options.openaiConcurrencyLimit)
+    await this.reviewPullRequest(
+      commenter,
+      lightBot,
+      heavyBot,
+      options,
+      prompts
+    )
+    await this.enforceReviewGate(commenter, options)
+  }
+
+  // Fails the run while too many of our review comments are open on the pull
+  // request. This counts what is on the pull request rather than what this run
+  // posted: incremental runs only review the new commits, and a push touching
+  // nothing reviewable skips the review entirely, so either would otherwise
+  // turn the check green.
+  private async enforceReviewGate(
+    commenter: Commenter,
+    options: Options
+  ): Promise<void> {
+    const pullRequest = getPullRequestPayload()
+    if (
+      options.failOnReviewComments <= 0 ||
+      !isPullRequestEvent() ||
+      pullRequest == null
+    ) {
+      return
+    }
+
+    let outstanding: number
+    try {
+      outstanding = await commenter.countOutstandingReviewComments(
+        pullRequest.number
+      )
+    } catch (error: unknown) {
+      setFailed(
+        `Could not count open review comments for fail_on_review_comments: ${error}`
+      )
+      return
+    }
+    if (outstanding >= options.failOnReviewComments) {
+      setFailed(
+        `Reval has ${outstanding} open review comments on this pull request (fail_on_review_comments: ${options.failOnReviewComments})`
+      )
+    }
+  }
+
+  private async reviewPullRequest(
+    commenter: Commenter,
+    lightBot: ChatBot,
+    heavyBot: ChatBot,
+    options: Options,
+    prompts: PromptLibrary
+  ): Promise<void> {
+    const modelConcurrencyLimit = pLimit(options.modelConcurrencyLimit)
     const githubConcurrencyLimit = pLimit(options.githubConcurrencyLimit)
 
     if (!isPullRequestEvent()) {
@@ -121,13 +175,14 @@ export class ReviewOrchestrator {
       return
     }
 
-    const filesAndChanges = await fileProcessor.buildFileChanges(
-      selected,
-      pullRequest
-    )
+    const {selected: filesAndChanges, generated} =
+      await fileProcessor.filterGeneratedFiles(
+        await fileProcessor.buildFileChanges(selected, pullRequest),
+        pullRequest
+      )
 
     if (filesAndChanges.length === 0) {
-      error('Skipped: no files to review')
+      info('Skipped: no files to review')
       return
     }
 
@@ -156,6 +211,18 @@ ${
 
 * ${ignored.map(file => file.filename).join('\n* ')}
 
+</details>
+`
+    : ''
+}
+${
+  generated.length > 0
+    ? `
+<details>
+<summary>Generated files skipped (${generated.length})</summary>
+
+* ${generated.join('\n* ')}
+
 </details>
 `
     : ''
@@ -200,7 +267,11 @@ ${
       }
 
       try {
-        const [summarizeResp] = await lightBot.chat(summarizePrompt, {})
+        const [summarizeResp] = await lightBot.chat(
+          summarizePrompt,
+          {},
+          filename
+        )
 
         if (summarizeResp === '') {
           info('summarize: nothing obtained from openai')
@@ -231,14 +302,18 @@ ${
       }
     }
 
-    const summaryPromises = []
+    const summaryPromises: Array<Promise<[string, string, boolean] | null>> = []
     const skippedFiles: string[] = []
+    let summariesDone = 0
     for (const [filename, fileContent, fileDiff] of filesAndChanges) {
       if (options.maxFiles <= 0 || summaryPromises.length < options.maxFiles) {
         summaryPromises.push(
-          openaiConcurrencyLimit(
-            async () => await doSummary(filename, fileContent, fileDiff)
-          )
+          modelConcurrencyLimit(async () => {
+            const result = await doSummary(filename, fileContent, fileDiff)
+            summariesDone += 1
+            info(`summarize: ${summariesDone}/${summaryPromises.length} files`)
+            return result
+          })
         )
       } else {
         skippedFiles.push(filename)
@@ -349,6 +424,7 @@ ${
 }
 `
 
+    let reviewSummary: ReviewSummary | null = null
     if (!options.disableReview) {
       const filesAndChangesReview = filesAndChanges.filter(([filename]) => {
         const needsReview =
@@ -370,11 +446,12 @@ ${
       let lgtmCount = 0
       let reviewCount = 0
 
-      const reviewPromises = []
+      const reviewPromises: Array<Promise<void>> = []
+      let reviewsDone = 0
       for (const [filename, , , patches] of filesAndChangesReview) {
         if (options.maxFiles <= 0 || reviewPromises.length < options.maxFiles) {
           reviewPromises.push(
-            openaiConcurrencyLimit(async () => {
+            modelConcurrencyLimit(async () => {
               const result = await generateFileReview(
                 filename,
                 patches,
@@ -391,6 +468,8 @@ ${
               if (result.skippedDueToSize) {
                 reviewsSkipped.push(`${filename} (diff too large)`)
               }
+              reviewsDone += 1
+              info(`review: ${reviewsDone}/${reviewPromises.length} files`)
             })
           )
         } else {
@@ -430,7 +509,7 @@ ${
 * LGTM: ${lgtmCount}
 
 </details>
-
+${heavyBot.getUsage().renderMarkdown()}
 ---
 
 <details>
@@ -459,8 +538,31 @@ ${
         commits[commits.length - 1]?.sha ?? pullRequest.head.sha,
         statusMsg
       )
+
+      reviewSummary = {
+        repository: `${context.repo.owner}/${context.repo.repo}`,
+        pullRequestNumber: pullRequest.number,
+        pullRequestTitle: pullRequest.title,
+        pullRequestUrl: pullRequest.html_url ?? '',
+        filesReviewed: filesAndChangesReview.length,
+        reviewComments: reviewCount,
+        lgtmComments: lgtmCount,
+        failedFiles: reviewsFailed
+      }
     }
 
     await commenter.comment(`${summarizeComment}`, SUMMARIZE_TAG, 'replace')
+
+    // Notify only once the summary comment, which records the reviewed
+    // commits, has been written.
+    if (reviewSummary != null) {
+      await new ReviewNotifier({
+        webhookUrl: options.notifyWebhookUrl,
+        slackWebhookUrl: options.notifySlackWebhookUrl,
+        minReviewComments: options.notifyMinReviewComments
+      }).notify(reviewSummary)
+    }
+
+    info(`token usage: ${heavyBot.getUsage().string()}`)
   }
 }
@@ -0,0 +1,78 @@
+import assert from 'node:assert/strict'
+import {test} from 'node:test'
+
+import {type ChatBot} from '../bot/chat-bot'
+import {AnthropicProvider} from '../bot/providers/anthropic-provider'
+import {type Commenter} from '../github/commenter'
+import {Options} from '../config/options'
+import {type PromptLibrary} from '../prompts/templates'
+import {Inputs} from '../shared/inputs'
+import {generateFileReview} from './reviewer'
+import {getTokenCount} from './tokenizer'
+
+const anthropicOptions = (): Options => {
+  const options = new Options({
+    systemMessage: '',
+    language: 'en-US',
+    summarize: '',
+    summarizeReleaseNotes: '',
+    aiProvider: 'anthropic',
+    model: '',
+    openaiModel: '',
+    geminiModel: ''
+  })
+  const provider = new AnthropicProvider(
+    {
+      apiKey: 'key',
+      model: options.anthropicModel,
+      temperature: 0,
+      timeout: 1000,
+      retries: 0
+    },
+    '',
+    'en-US'
+  )
+  options.updateTokenLimits(provider.getModelInfo())
+  return options
+}
+
+const largePatch = (): string =>
+  Array.from(
+    {length: 3000},
+    (_, i) => `+  const value${i} = computeChecksum(buffer, offset + ${i})`
+  ).join('\n')
+
+test('reviews a patch larger than 4000 tokens with Anthropic', async () => {
+  const patch = largePatch()
+  assert.ok(getTokenCount(patch) > 4000)
+
+  const prompts: string[] = []
+  const bot = {
+    chat: async (message: string) => {
+      prompts.push(message)
+      return ['LGTM!', {}]
+    }
+  } as unknown as ChatBot
+  const commenter = {
+    getCommentChainsWithinRange: async () => '',
+    bufferReviewComment: async () => {}
+  } as unknown as Commenter
+  const templates = {
+    renderReviewFileDiff: (inputs: Inputs) => inputs.patches
+  } as unknown as PromptLibrary
+
+  const result = await generateFileReview(
+    'src/checksum.ts',
+    [[1, 3000, patch]],
+    {number: 1},
+    commenter,
+    anthropicOptions(),
+    templates,
+    bot,
+    new Inputs()
+  )
+
+  assert.equal(result.skippedDueToSize, false)
+  assert.equal(prompts.length, 1)
+  assert.ok(prompts[0].includes(patch))
+})
@@ -125,7 +125,8 @@ ${commentChain}
   try {
     const [response] = await bot.chat(
       prompts.renderReviewFileDiff(clonedInputs),
-      {}
+      {},
+      filename
     )
     if (response === '') {
       info('review: nothing obtained from openai')
@@ -0,0 +1,64 @@
+import assert from 'node:assert/strict'
+import {test} from 'node:test'
+
+import {Inputs} from './inputs'
+
+test('replaces every occurrence of a placeholder', () => {
+  const inputs = new Inputs()
+  inputs.filename = 'main.go'
+
+  assert.equal(
+    inputs.render('$filename, again $filename'),
+    'main.go, again main.go'
+  )
+})
+
+test('does not expand placeholders inside substituted values', () => {
+  const inputs = new Inputs()
+  inputs.description = 'mentions $diff and $& literally'
+  inputs.diff = 'the diff'
+
+  assert.equal(
+    inputs.render('$description\n$diff'),
+    'mentions $diff and $& literally\nthe diff'
+  )
+})
+
+test('tells $comment and $comment_chain apart', () => {
+  const inputs = new Inputs()
+  inputs.comment = 'the comment'
+  inputs.commentChain = 'the chain'
+
+  assert.equal(
+    inputs.render('$comment_chain / $comment'),
+    'the chain / the comment'
+  )
+})
+
+test('renders the programming language of the file', () => {
+  const inputs = new Inputs()
+
+  inputs.filename = 'pkg/worker.go'
+  assert.equal(inputs.render('$language'), 'Go')
+  inputs.filename = 'scripts/Build.PY'
+  assert.equal(inputs.render('$language'), 'Python')
+  inputs.filename = 'Makefile'
+  assert.equal(inputs.render('$language'), 'unknown')
+})
+
+test('renders the rule taxonomy', () => {
+  const inputs = new Inputs()
+  inputs.ruleTaxonomy = '- `race`: data races'
+
+  assert.equal(
+    inputs.clone().render('Categories:\n$rule_taxonomy'),
+    'Categories:\n- `race`: data races'
+  )
+})
+
+test('leaves empty and unknown placeholders as written', () => {
+  assert.equal(
+    new Inputs().render('$filename $language $start-$end'),
+    '$filename $language $start-$end'
+  )
+})
@@ -1,3 +1,38 @@
+// Programming languages by file extension, for the $language placeholder.
+const LANGUAGES: Record<string, string> = {
+  c: 'C',
+  cc: 'C++',
+  cpp: 'C++',
+  cs: 'C#',
+  go: 'Go',
+  h: 'C',
+  hpp: 'C++',
+  java: 'Java',
+  js: 'JavaScript',
+  jsx: 'JavaScript',
+  kt: 'Kotlin',
+  mjs: 'JavaScript',
+  php: 'PHP',
+  py: 'Python',
+  rb: 'Ruby',
+  rs: 'Rust',
+  scala: 'Scala',
+  sh: 'Shell',
+  sql: 'SQL',
+  swift: 'Swift',
+  ts: 'TypeScript',
+  tsx: 'TypeScript'
+}
+
+const languageOf = (filename: string): string => {
+  if (!filename) {
+    return ''
+  }
+  const dot = filename.lastIndexOf('.')
+  const extension = dot === -1 ? '' : filename.slice(dot + 1).toLowerCase()
+  return LANGUAGES[extension] ?? 'unknown'
+}
+
 export class Inputs {
   systemMessage: string
   title: string
@@ -11,6 +46,7 @@ export class Inputs {
   diff: string
   commentChain: string
   comment: string
+  ruleTaxonomy: string
 
   constructor(
     systemMessage = '',
@@ -24,7 +60,8 @@ export class Inputs {
     patches = '',
     diff = 'no diff',
     commentChain = 'no other comments on this patch',
-    comment = 'no comment provided'
+    comment = 'no comment provided',
+    ruleTaxonomy = ''
   ) {
     this.systemMessage = systemMessage
     this.title = title
@@ -38,6 +75,7 @@ export class Inputs {
     this.diff = diff
     this.commentChain = commentChain
     this.comment = comment
+    this.ruleTaxonomy = ruleTaxonomy
   }
 
   clone(): Inputs {
@@ -53,7 +91,8 @@ export class Inputs {
       this.patches,
       this.diff,
       this.commentChain,
-      this.comment
+      this.comment,
+      this.ruleTaxonomy
     )
   }
 
@@ -61,42 +100,28 @@ export class Inputs {
     if (!content) {
       return ''
     }
-    if (this.systemMessage) {
-      content = content.replace('$system_message', this.systemMessage)
-    }
-    if (this.title) {
-      content = content.replace('$title', this.title)
-    }
-    if (this.description) {
-      content = content.replace('$description', this.description)
-    }
-    if (this.rawSummary) {
-      content = content.replace('$raw_summary', this.rawSummary)
-    }
-    if (this.shortSummary) {
-      content = content.replace('$short_summary', this.shortSummary)
-    }
-    if (this.filename) {
-      content = content.replace('$filename', this.filename)
-    }
-    if (this.fileContent) {
-      content = content.replace('$file_content', this.fileContent)
-    }
-    if (this.fileDiff) {
-      content = content.replace('$file_diff', this.fileDiff)
-    }
-    if (this.patches) {
-      content = content.replace('$patches', this.patches)
-    }
-    if (this.diff) {
-      content = content.replace('$diff', this.diff)
-    }
-    if (this.commentChain) {
-      content = content.replace('$comment_chain', this.commentChain)
-    }
-    if (this.comment) {
-      content = content.replace('$comment', this.comment)
-    }
-    return content
+    const values = new Map<string, string>([
+      ['system_message', this.systemMessage],
+      ['title', this.title],
+      ['description', this.description],
+      ['raw_summary', this.rawSummary],
+      ['short_summary', this.shortSummary],
+      ['filename', this.filename],
+      ['language', languageOf(this.filename)],
+      ['file_content', this.fileContent],
+      ['file_diff', this.fileDiff],
+      ['patches', this.patches],
+      ['diff', this.diff],
+      ['comment_chain', this.commentChain],
+      ['comment', this.comment],
+      ['rule_taxonomy', this.ruleTaxonomy]
+    ])
+    // A single pass replaces every occurrence, and never looks for
+    // placeholders in substituted text such as a diff or a comment. Empty
+    // values and unknown names are left as written.
+    return content.replace(
+      /\$([a-z_]+)/g,
+      (placeholder, name: string) => values.get(name) || placeholder
+    )
   }
 }
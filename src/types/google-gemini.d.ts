@@ -1 +0,0 @@
-declare module '@google/generative-ai'
@@ -0,0 +1,121 @@
+import assert from 'node:assert/strict'
+import {createServer, type ServerResponse} from 'node:http'
+import {type AddressInfo} from 'node:net'
+import {after, before, beforeEach, test} from 'node:test'
+
+import {postJSON} from './http-client'
+import {type RateLimiter} from './rate-limiter'
+
+// Each request is answered by the next handler, the last one repeating.
+let handlers: Array<(res: ServerResponse) => void> = []
+let requests = 0
+let url = ''
+
+const server = createServer((req, res) => {
+  req.resume()
+  handlers[Math.min(requests, handlers.length - 1)](res)
+  requests += 1
+})
+
+before(async () => {
+  await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve))
+  url = `http://127.0.0.1:${(server.address() as AddressInfo).port}/`
+})
+
+after(() => {
+  server.closeAllConnections()
+  server.close()
+})
+
+beforeEach(() => {
+  requests = 0
+})
+
+const reply =
+  (status: number, body: unknown, headers: Record<string, string> = {}) =>
+  (res: ServerResponse) => {
+    res.writeHead(status, {'content-type': 'application/json', ...headers})
+    res.end(JSON.stringify(body))
+  }
+
+// Counts the slots taken and the deferrals asked of the limiter.
+const fakeLimiter = (): {
+  limiter: RateLimiter
+  waits: () => number
+  deferrals: number[]
+} => {
+  let waits = 0
+  const deferrals: number[] = []
+  const limiter = {
+    wait: async () => {
+      waits += 1
+    },
+    deferFor: (delay: number) => {
+      deferrals.push(delay)
+    }
+  } as unknown as RateLimiter
+  return {limiter, waits: () => waits, deferrals}
+}
+
+const post = async (
+  retries: number,
+  rateLimiter?: RateLimiter,
+  timeout = 2000
+): Promise<{ok?: boolean}> =>
+  postJSON<{ok?: boolean; error?: string}>(
+    'Test',
+    url,
+    {},
+    {},
+    {timeout, retries, rateLimiter},
+    body => body.error
+  )
+
+test('times out when the body stalls after the headers', async () => {
+  handlers = [
+    res => {
+      res.writeHead(200, {'content-type': 'application/json'})
+      res.write('{"ok":')
+    }
+  ]
+
+  const startedAt = Date.now()
+  await assert.rejects(post(0, undefined, 300))
+  assert.ok(Date.now() - startedAt < 2000)
+})
+
+test('takes a rate limiter slot for every attempt', async () => {
+  handlers = [reply(503, {error: 'overloaded'}), reply(200, {ok: true})]
+  const {limiter, waits} = fakeLimiter()
+
+  assert.deepEqual(await post(1, limiter), {ok: true})
+  assert.equal(requests, 2)
+  assert.equal(waits(), 2)
+})
+
+test('defers the next attempts by Retry-After on 429', async () => {
+  handlers = [
+    reply(429, {error: 'slow down'}, {'retry-after': '7'}),
+    reply(200, {ok: true})
+  ]
+  const {limiter, deferrals} = fakeLimiter()
+
+  assert.deepEqual(await post(1, limiter), {ok: true})
+  assert.deepEqual(deferrals, [7000])
+})
+
+test('gives up when Retry-After is too long to wait', async () => {
+  handlers = [reply(429, {error: 'slow down'}, {'retry-after': '3600'})]
+  const {limiter, deferrals} = fakeLimiter()
+
+  await assert.rejects(post(3, limiter), /giving up/)
+  assert.equal(requests, 1)
+  assert.deepEqual(deferrals, [])
+})
+
+test('does not retry client errors', async () => {
+  handlers = [reply(401, {error: 'bad key'})]
+
+  await assert.rejects(post(3), /Test API returned 401: bad key/)
+  assert.equal(requests, 1)
+})
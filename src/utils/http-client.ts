@@ -0,0 +1,172 @@
+import './fetch-polyfill'
+import {info} from '@actions/core'
+import pRetry, {AbortError} from 'p-retry'
+
+import {RateLimiter} from './rate-limiter'
+
+export interface RequestPolicy {
+  timeout: number
+  retries: number
+  // shared by every request to a provider; each attempt takes a slot
+  rateLimiter?: RateLimiter
+}
+
+// Longer Retry-After waits fail the request rather than stall the review.
+const MAX_RETRY_AFTER_MS = 60000
+
+// Rate limits, timeouts and server errors are transient; any other 4xx (bad
+// key, unknown model, malformed request) fails the same way on every retry.
+const isRetryableStatus = (status: number): boolean =>
+  status === 408 || status === 429 || status >= 500
+
+// Retry-After is either a number of seconds or an HTTP date.
+const parseRetryAfter = (
+  value: string | null | undefined
+): number | undefined => {
+  if (!value) {
+    return undefined
+  }
+  const seconds = Number(value)
+  if (Number.isFinite(seconds)) {
+    return Math.max(0, seconds * 1000)
+  }
+  const date = Date.parse(value)
+  return Number.isNaN(date) ? undefined : Math.max(0, date - Date.now())
+}
+
+// fetch that aborts once timeout ms pass before read has consumed the
+// response, so a body that stalls after the headers cannot hang either.
+export const fetchWithTimeout = async <T>(
+  url: string,
+  init: RequestInit,
+  timeout: number,
+  read: (res: Response) => Promise<T>
+): Promise<T> => {
+  const controller = new AbortController()
+  const timer = setTimeout(() => controller.abort(), timeout)
+
+  try {
+    const res = await fetch(url, {...init, signal: controller.signal})
+    return await read(res)
+  } finally {
+    clearTimeout(timer)
+  }
+}
+
+const logFailedAttempt =
+  (name: string) =>
+  (error: {attemptNumber: number; retriesLeft: number; message: string}) => {
+    info(
+      `${name} request failed (attempt ${error.attemptNumber}, ${error.retriesLeft} retries left): ${error.message}`
+    )
+  }
+
+// Runs attempt with retries under policy. Every attempt, retries included,
+// first waits for a slot from the provider's rate limiter.
+const retrying = async <T>(
+  name: string,
+  policy: RequestPolicy,
+  attempt: (rateLimiter: RateLimiter) => Promise<T>
+): Promise<T> => {
+  const rateLimiter = policy.rateLimiter ?? new RateLimiter(0)
+  return pRetry(
+    async () => {
+      await rateLimiter.wait()
+      return attempt(rateLimiter)
+    },
+    {retries: policy.retries, onFailedAttempt: logFailedAttempt(name)}
+  )
+}
+
+// Classifies a failed request for pRetry: AbortError stops retrying, and a
+// Retry-After on a transient failure holds back the provider's next
+// attempts for as long as the server asked.
+const toRetryError = (
+  error: Error,
+  status: number,
+  retryAfter: string | null | undefined,
+  rateLimiter: RateLimiter
+): Error => {
+  if (!isRetryableStatus(status)) {
+    return new AbortError(error)
+  }
+  const delay = parseRetryAfter(retryAfter)
+  if (delay !== undefined) {
+    if (delay > MAX_RETRY_AFTER_MS) {
+      return new AbortError(
+        `${error.message} (asked to retry after ${delay} ms, giving up)`
+      )
+    }
+    rateLimiter.deferFor(delay)
+  }
+  return error
+}
+
+// Retries a request made by a provider SDK under the same rules as postJSON.
+// statusOf extracts the HTTP status from the SDK's error; errors without one
+// (network failures, timeouts) are retried. retryAfterOf extracts the
+// Retry-After header, if the SDK exposes it.
+export const withRetries = async <T>(
+  name: string,
+  request: () => Promise<T>,
+  policy: RequestPolicy,
+  statusOf: (error: any) => number | undefined,
+  retryAfterOf: (error: any) => string | null | undefined = () => undefined
+): Promise<T> =>
+  retrying(name, policy, async rateLimiter => {
+    try {
+      return await request()
+    } catch (error: any) {
+      const status = statusOf(error)
+      if (status === undefined) {
+        throw error
+      }
+      throw toRetryError(error, status, retryAfterOf(error), rateLimiter)
+    }
+  })
+
+// Error bodies are not always JSON, and are only read for their message.
+const parseJSON = (text: string): any => {
+  try {
+    return JSON.parse(text)
+  } catch (e) {
+    return {}
+  }
+}
+
+export const postJSON = async <T>(
+  name: string,
+  url: string,
+  headers: Record<string, string>,
+  payload: unknown,
+  policy: RequestPolicy,
+  describeError: (body: T) => string | undefined
+): Promise<T> =>
+  retrying(name, policy, async rateLimiter => {
+    const {res, body} = await fetchWithTimeout(
+      url,
+      {
+        method: 'POST',
+        headers: {'content-type': 'application/json', ...headers},
+        body: JSON.stringify(payload)
+      },
+      policy.timeout,
+      async response => ({
+        res: response,
+        body: parseJSON(await response.text()) as T
+      })
+    )
+
+    if (!res.ok) {
+      const message = `${name} API returned ${res.status}: ${
+        describeError(body) ?? res.statusText
+      }`
+      throw toRetryError(
+        new Error(message),
+        res.status,
+        res.headers.get('retry-after'),
+        rateLimiter
+      )
+    }
+    return body
+  })
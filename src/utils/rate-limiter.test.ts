@@ -0,0 +1,23 @@
+import assert from 'node:assert/strict'
+import {test} from 'node:test'
+
+import {RateLimiter} from './rate-limiter'
+
+test('spaces request starts by the limit', async () => {
+  const limiter = new RateLimiter(600)
+
+  const startedAt = Date.now()
+  await limiter.wait()
+  await limiter.wait()
+  await limiter.wait()
+  assert.ok(Date.now() - startedAt >= 190)
+})
+
+test('holds back requests after a deferral even when unlimited', async () => {
+  const limiter = new RateLimiter(0)
+  limiter.deferFor(200)
+
+  const startedAt = Date.now()
+  await limiter.wait()
+  assert.ok(Date.now() - startedAt >= 190)
+})
@@ -0,0 +1,25 @@
+// Spaces request starts evenly so no more than perMinute begin in any
+// minute. A limit of 0 or less disables the spacing, but requests still
+// wait out a deferral.
+export class RateLimiter {
+  private nextStart = 0
+
+  constructor(private readonly perMinute: number) {}
+
+  async wait(): Promise<void> {
+    const now = Date.now()
+    const start = Math.max(now, this.nextStart)
+    if (this.perMinute > 0) {
+      this.nextStart = start + 60000 / this.perMinute
+    }
+    if (start > now) {
+      await new Promise(resolve => setTimeout(resolve, start - now))
+    }
+  }
+
+  // Holds back requests that have not started yet until delay ms from now,
+  // as a server asks with Retry-After.
+  deferFor(delay: number): void {
+    this.nextStart = Math.max(this.nextStart, Date.now() + delay)
+  }
+}
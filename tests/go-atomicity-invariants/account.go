@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Account keeps two fields that must always change together: every
+// successful deposit or withdrawal moves balance and bumps txCount.
+type Account struct {
+	mu      sync.Mutex
+	balance int
+	txCount int
+}
+
+func (a *Account) Deposit(amount int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.balance += amount
+	a.txCount++
+}
+
+func (a *Account) Withdraw(amount int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.balance < amount {
+		return false
+	}
+	a.balance -= amount
+	a.txCount++
+	return true
+}
+
+func (a *Account) Balance() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.balance
+}
+
+// TransactionCount reads txCount without the lock, racing with the locked
+// writes in Deposit and Withdraw.
+func (a *Account) TransactionCount() int {
+	//reval:expect race id=RC001 fields=txCount
+	return a.txCount // Data race - unlocked read of txCount
+}
+
+// Snapshot composes two separate reads, so other goroutines can complete
+// transactions between them and the pair never existed together. Locking
+// TransactionCount would fix the data race but not this.
+func (a *Account) Snapshot() (int, int) {
+	//reval:expect atomicity id=AT001 fields=balance,txCount
+	return a.Balance(), a.TransactionCount() // Atomicity violation - torn read
+}
+
+func main() {
+	account := &Account{}
+	locked := &LockedAccount{}
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				account.Deposit(10)
+				account.Withdraw(5)
+				locked.Deposit(10)
+				locked.Withdraw(5)
+			}
+		}()
+	}
+
+	// Deposits add 10 and withdrawals take 5, so every consistent state has
+	// balance = 10*d - 5*w with txCount = d + w, i.e. balance+5*txCount is a
+	// multiple of 15.
+	done := make(chan struct{})
+	torn := make(chan int)
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-done:
+				torn <- n
+				return
+			default:
+			}
+			balance, count := account.Snapshot()
+			if (balance+5*count)%15 != 0 {
+				n++
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	fmt.Printf("torn snapshots: %d\n", <-torn)
+
+	balance, count := account.Snapshot()
+	fmt.Printf("Account: balance=%d transactions=%d\n", balance, count)
+	balance, count = locked.Snapshot()
+	fmt.Printf("LockedAccount: balance=%d transactions=%d\n", balance, count)
+}
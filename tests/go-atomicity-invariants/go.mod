@@ -0,0 +1,7 @@
+module atomicity-invariants-test
+
+go 1.21
+
+require (
+	// No external dependencies needed for this atomicity invariant demo
+)
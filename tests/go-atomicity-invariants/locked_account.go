@@ -0,0 +1,37 @@
+package main
+
+import "sync"
+
+// LockedAccount is the correctly synchronized counterpart of Account. It
+// should produce no findings and exists to measure reviewer precision.
+type LockedAccount struct {
+	mu      sync.Mutex
+	balance int
+	txCount int
+}
+
+func (a *LockedAccount) Deposit(amount int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.balance += amount
+	a.txCount++
+}
+
+func (a *LockedAccount) Withdraw(amount int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.balance < amount {
+		return false
+	}
+	a.balance -= amount
+	a.txCount++
+	return true
+}
+
+// Snapshot returns both fields from a single critical section so callers
+// never see a balance and count from different transactions.
+func (a *LockedAccount) Snapshot() (int, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.balance, a.txCount
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// closeFromProducer lets every producer close the shared channel when it
+// finishes, so whichever producer is slower sends on a closed channel.
+func closeFromProducer() {
+	ch := make(chan int)
+	var wg sync.WaitGroup
+
+	for p := 1; p <= 2; p++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				ch <- id*10 + i
+			}
+			//reval:expect channel id=CH001
+			close(ch) // Bug #1 - one of several senders closes the channel
+		}(p)
+	}
+
+	for v := range ch {
+		fmt.Println("got", v)
+	}
+	wg.Wait()
+}
+
+// closeFromReceiver closes the channel from the consuming side to stop the
+// producer, which is still sending.
+func closeFromReceiver() {
+	ch := make(chan int)
+
+	go func() {
+		for i := 0; ; i++ {
+			ch <- i
+		}
+	}()
+
+	for v := range ch {
+		fmt.Println("got", v)
+		if v == 3 {
+			//reval:expect channel id=CH002
+			close(ch) // Bug #2 - receiver closes a channel it does not own
+			break
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+}
+
+var scenarios = map[string]func(){
+	"close-from-producer": closeFromProducer,
+	"close-from-receiver": closeFromReceiver,
+}
+
+func main() {
+	if len(os.Args) < 2 || scenarios[os.Args[1]] == nil {
+		fmt.Println("usage: go run . close-from-producer|close-from-receiver")
+		os.Exit(2)
+	}
+	scenarios[os.Args[1]]()
+}
@@ -0,0 +1,7 @@
+module channel-misuse-test
+
+go 1.21
+
+require (
+	// No external dependencies needed for this channel misuse demo
+)
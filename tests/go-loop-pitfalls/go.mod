@@ -0,0 +1,9 @@
+module loop-pitfalls-test
+
+// go 1.21 keeps per-loop (not per-iteration) loop variables, which is what
+// makes the capture bugs below observable.
+go 1.21
+
+require (
+	// No external dependencies needed for this loop pitfalls demo
+)
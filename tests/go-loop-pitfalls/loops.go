@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// countLines defers every Close inside the loop, so all files stay open
+// until the function returns instead of after each iteration.
+func countLines(paths []string) (int, error) {
+	total := 0
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		//reval:expect defer-in-loop id=DF001
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// sumSquares launches one goroutine per value, but every goroutine reads
+// the shared loop variable rather than its own copy.
+func sumSquares(n int) int {
+	var wg sync.WaitGroup
+	results := make(chan int, n)
+
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			//reval:expect loop-capture id=LC001
+			results <- i * i
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	sum := 0
+	for r := range results {
+		sum += r
+	}
+	return sum
+}
+
+// greeters returns closures that all capture the same name variable.
+func greeters(names []string) []func() string {
+	var fns []func() string
+	for _, name := range names {
+		fns = append(fns, func() string {
+			//reval:expect loop-capture id=LC002
+			return "hello " + name
+		})
+	}
+	return fns
+}
+
+func main() {
+	dir, err := os.MkdirTemp("", "loop-pitfalls")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	var paths []string
+	for i := 0; i < 100; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file-%d.txt", i))
+		if err := os.WriteFile(path, []byte("a\nb\nc\n"), 0o600); err != nil {
+			fmt.Println(err)
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	lines, err := countLines(paths)
+	fmt.Println("lines:", lines, err)
+
+	fmt.Println("sum of squares 1..10:", sumSquares(10), "(expected 385)")
+
+	for _, greet := range greeters([]string{"alice", "bob", "carol"}) {
+		fmt.Println(greet())
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import "time"
+
+// drainWithTimeout allocates a new timer with every iteration; on Go
+// versions before 1.23 none of them are collected until they fire.
+func drainWithTimeout(events <-chan int) int {
+	received := 0
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return received
+			}
+			received++
+		//reval:expect timer-leak id=TL001
+		case <-time.After(time.Minute):
+			return received
+		}
+	}
+}
@@ -0,0 +1,7 @@
+module timer-leaks-test
+
+go 1.21
+
+require (
+	// No external dependencies needed for this timer leak demo
+)
@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+func main() {
+	events := make(chan int)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			events <- i
+		}
+		close(events)
+	}()
+	fmt.Println("events received:", drainWithTimeout(events))
+
+	calls := 0
+	fmt.Println("polls until ready:", pollUntil(func() bool {
+		calls++
+		return calls == 5
+	}))
+
+	fmt.Println("results collected:", len(collect(10)), "of 10")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// collect waits for its workers by sleeping and hoping they are done,
+// instead of synchronizing with them.
+func collect(workers int) []int {
+	var mu sync.Mutex
+	var results []int
+
+	for i := 0; i < workers; i++ {
+		go func(n int) {
+			time.Sleep(time.Millisecond * time.Duration(n%3))
+			mu.Lock()
+			results = append(results, n)
+			mu.Unlock()
+		}(i)
+	}
+
+	//reval:expect sleep-sync id=SS001
+	time.Sleep(time.Millisecond * 1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	return results
+}
@@ -0,0 +1,17 @@
+package main
+
+import "time"
+
+// pollUntil creates a ticker but returns without stopping it.
+func pollUntil(ready func() bool) int {
+	//reval:expect ticker-leak id=TK001
+	ticker := time.NewTicker(time.Millisecond)
+	polls := 0
+	for range ticker.C {
+		polls++
+		if ready() {
+			return polls
+		}
+	}
+	return polls
+}
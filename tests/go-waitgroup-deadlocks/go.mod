@@ -0,0 +1,7 @@
+module waitgroup-deadlocks-test
+
+go 1.21
+
+require (
+	// No external dependencies needed for this WaitGroup misuse demo
+)
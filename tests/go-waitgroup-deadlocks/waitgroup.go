@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// addInsideGoroutine calls wg.Add from the spawned goroutine, so Wait can
+// return before any worker has registered itself.
+func addInsideGoroutine() int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	total := 0
+
+	for i := 0; i < 10; i++ {
+		go func(n int) {
+			//reval:expect deadlock id=DL001
+			wg.Add(1) // Deadlock #1 - Add inside the goroutine instead of before go
+			defer wg.Done()
+			mu.Lock()
+			total += n
+			mu.Unlock()
+		}(i)
+	}
+
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	return total
+}
+
+func process(n int) error {
+	if n%3 == 0 {
+		return errors.New("multiple of three")
+	}
+	return nil
+}
+
+// missingDoneOnError returns early from a worker without calling Done, so
+// Wait blocks forever once any job fails.
+func missingDoneOnError() {
+	var wg sync.WaitGroup
+
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(n int) {
+			if err := process(n); err != nil {
+				fmt.Println("job failed:", err)
+				//reval:expect deadlock id=DL002
+				return // Deadlock #2 - Done unreachable on the error path
+			}
+			wg.Done()
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// waitHoldingLock waits for workers while holding the mutex every worker
+// needs before it can call Done.
+func waitHoldingLock() {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	results := make([]int, 0, 5)
+
+	mu.Lock()
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			mu.Lock()
+			results = append(results, n*n)
+			mu.Unlock()
+		}(i)
+	}
+	//reval:expect deadlock id=DL003
+	wg.Wait() // Deadlock #3 - Wait while holding mu, which the workers need
+	mu.Unlock()
+
+	fmt.Println(results)
+}
+
+// doubleDone calls Done once explicitly and again through defer, driving
+// the counter negative.
+func doubleDone() {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fmt.Println("working")
+		//reval:expect deadlock id=DL004
+		wg.Done() // Deadlock #4 - second Done for a single Add
+	}()
+
+	wg.Wait()
+}
+
+var scenarios = map[string]func(){
+	"add-inside":        func() { fmt.Println("total:", addInsideGoroutine()) },
+	"missing-done":      missingDoneOnError,
+	"wait-holding-lock": waitHoldingLock,
+	"double-done":       doubleDone,
+}
+
+func main() {
+	if len(os.Args) < 2 || scenarios[os.Args[1]] == nil {
+		fmt.Println("usage: go run . add-inside|missing-done|wait-holding-lock|double-done")
+		os.Exit(2)
+	}
+	scenarios[os.Args[1]]()
+}
@@ -0,0 +1,49 @@
+const sleep = ms => new Promise(resolve => setTimeout(resolve, ms))
+
+let balance = 100
+
+async function fetchUser(id) {
+  await sleep(1)
+  if (id < 0) {
+    throw new Error(`invalid user id ${id}`)
+  }
+  return {id, name: `user-${id}`}
+}
+
+// loadUsers fetches users one at a time although the calls are independent.
+async function loadUsers(ids) {
+  const users = []
+  for (const id of ids) {
+    //reval:expect await-in-loop id=AL001
+    users.push(await fetchUser(id))
+  }
+  return users
+}
+
+// withdraw checks the balance, yields, then writes it, so two concurrent
+// calls can both pass the check.
+async function withdraw(amount) {
+  if (balance >= amount) {
+    await sleep(1)
+    //reval:expect race id=RC001
+    balance -= amount
+  }
+}
+
+// prefetch warms a cache but neither awaits nor handles the promise.
+function prefetch(id) {
+  //reval:expect unhandled-rejection id=UR001
+  fetchUser(id)
+}
+
+async function main() {
+  const users = await loadUsers([1, 2, 3])
+  console.log('users:', users.length)
+
+  await Promise.all([withdraw(80), withdraw(80)])
+  console.log('balance:', balance)
+
+  prefetch(-1)
+}
+
+main()
@@ -0,0 +1,47 @@
+import json
+import threading
+
+counter = 0
+
+
+def read_config(path):
+    # reval:expect leak id=RL001
+    f = open(path)
+    return json.load(f)
+
+
+# reval:expect mutable-default id=MD001
+def add_item(item, items=[]):
+    items.append(item)
+    return items
+
+
+def parse_port(value):
+    try:
+        return int(value)
+    # reval:expect bare-except id=BE001
+    except:
+        return 8080
+
+
+def increment(times):
+    global counter
+    for _ in range(times):
+        # reval:expect race id=RC001
+        counter += 1
+
+
+def main():
+    threads = [threading.Thread(target=increment, args=(100000,)) for _ in range(4)]
+    for t in threads:
+        t.start()
+    for t in threads:
+        t.join()
+
+    print("counter:", counter)
+    print("items:", add_item("a"), add_item("b"))
+    print("port:", parse_port("not-a-port"))
+
+
+if __name__ == "__main__":
+    main()